@@ -1,17 +1,11 @@
 package clients
 
 import (
-	"bytes"
-	"encoding/json"
+	stdcontext "context"
 	"errors"
-	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
-	"strings"
 
 	microappCtx "github.com/islax/microapp/context"
-	microappError "github.com/islax/microapp/error"
 )
 
 // APIClient represents the actual client calling microservice
@@ -19,121 +13,71 @@ type APIClient struct {
 	AppName    string
 	BaseURL    string
 	HTTPClient *http.Client
-}
-
-// DoRequestWithResponseParam do request with response param
-func (apiClient *APIClient) DoRequestWithResponseParam(context microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload map[string]interface{}, out interface{}) error {
-	apiURL := apiClient.BaseURL + url
-	var body io.Reader
-	if payload != nil {
-		bytePayload, err := json.Marshal(payload)
-		if err != nil {
-			return microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to marshal payload: %w", err))
-		}
-		body = bytes.NewBuffer(bytePayload)
-	}
 
-	request, err := http.NewRequest(requestMethod, apiURL, body)
-	if err != nil {
-		return microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to create HTTP request: %w", err))
-	}
-
-	if rawToken != "" {
-		if strings.HasPrefix(rawToken, "Bearer") {
-			request.Header.Add("Authorization", rawToken)
-		} else {
-			request.Header.Add("Authorization", "Bearer "+rawToken)
-		}
-	}
-	request.Header.Set("X-Client", apiClient.AppName)
-	request.Header.Set("X-Correlation-ID", context.GetCorrelationID())
-	request.Header.Set("Content-Type", "application/json")
-
-	response, err := apiClient.HTTPClient.Do(request)
-	if err != nil {
-		return microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to invoke API: %w", err))
-	}
+	// Build, Sign, Send, ValidateResponse and Unmarshal are named interceptor
+	// phases modeled on the AWS SDK's handler lists. A request flows through
+	// Build, Sign, the interceptors registered via Use, ValidateResponse and
+	// Unmarshal, in that order; Send is innermost, wrapping the transport call
+	// itself. Register phase-specific behavior (e.g. a Sign interceptor
+	// that re-signs after a 401) by appending directly to the relevant list.
+	Build            []Interceptor
+	Sign             []Interceptor
+	Send             []Interceptor
+	ValidateResponse []Interceptor
+	Unmarshal        []Interceptor
+
+	// interceptors are general-purpose interceptors registered via Use. They
+	// run after Sign and before ValidateResponse, i.e. around the outgoing
+	// request and the raw response, which is where retries, circuit breaking,
+	// metrics and auth-token refresh belong.
+	interceptors []Interceptor
+
+	// RetryPolicy governs automatic retry of a request on a retryable
+	// failure. A nil RetryPolicy (the default) disables retries.
+	RetryPolicy *RetryPolicy
+
+	// Codec marshals request payloads and unmarshals response bodies whose
+	// Content-Type doesn't match a more specific registered Codec. A nil
+	// Codec (the default) preserves the client's historical JSON behavior.
+	//
+	// The legacy map-based methods (DoGet, DoPost, DoDelete, DoGetList and
+	// their *Ctx variants) always marshal/unmarshal through
+	// map[string]interface{}, which satisfies JSONCodec and MsgpackCodec but
+	// never proto.Message; setting Codec to ProtoCodec breaks every call to
+	// one of those methods. Use DoRequestWithCodec for a proto.Message
+	// payload instead of setting this client-wide.
+	Codec Codec
+
+	// TokenProvider, when set, supplies the bearer token for a call whose
+	// rawToken argument is empty, refreshing it as needed. On a 401 response
+	// the provider is invalidated and the request is retried once with a
+	// fresh token. DoMultipart, whose body is streamed, is not retried on a 401.
+	TokenProvider TokenProvider
+}
 
-	defer response.Body.Close()
-	if response.StatusCode > 300 { // All 3xx, 4xx, 5xx are considered errors
-		responseBodyString := ""
-		if responseBodyBytes, err := ioutil.ReadAll(response.Body); err != nil {
-			responseBodyString = string(responseBodyBytes)
-		}
-		return microappError.NewAPICallError(apiURL, &response.StatusCode, &responseBodyString, fmt.Errorf("Received non-success code: %v", response.StatusCode))
+// classifyTransportError maps a transport-level error from HTTPClient.Do to the
+// underlying context error when the context is what actually caused the failure,
+// so callers can use errors.Is(err, context.Canceled) / errors.Is(err, context.DeadlineExceeded)
+// instead of string-matching the transport error.
+func classifyTransportError(ctx stdcontext.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
 	}
+	return err
+}
 
-	if out != nil {
-		err = json.NewDecoder(response.Body).Decode(out)
-		if err != nil {
-			return microappError.NewAPICallError(apiURL, &response.StatusCode, nil, fmt.Errorf("Unable parse response payload: %w", err))
-		}
-	}
-	return nil
+// DoRequestWithResponseParam do request with response param
+func (apiClient *APIClient) DoRequestWithResponseParam(context microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload map[string]interface{}, out interface{}) error {
+	return apiClient.DoRequestWithResponseParamCtx(stdcontext.Background(), context, url, requestMethod, rawToken, payload, out)
 }
 
 func (apiClient *APIClient) doRequest(context microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload map[string]interface{}) (interface{}, error) {
-	apiURL := apiClient.BaseURL + url
-	var body io.Reader
-	if payload != nil {
-		bytePayload, err := json.Marshal(payload)
-		if err != nil {
-			return nil, microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to marshal payload: %w", err))
-		}
-		body = bytes.NewBuffer(bytePayload)
-	}
-
-	request, err := http.NewRequest(requestMethod, apiURL, body)
-	if err != nil {
-		return nil, microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to create HTTP request: %w", err))
-	}
-
-	if rawToken != "" {
-		if strings.HasPrefix(rawToken, "Bearer") {
-			request.Header.Add("Authorization", rawToken)
-		} else {
-			request.Header.Add("Authorization", "Bearer "+rawToken)
-		}
-	}
-	request.Header.Set("X-Client", apiClient.AppName)
-	request.Header.Set("X-Correlation-ID", context.GetCorrelationID())
-	request.Header.Set("Content-Type", "application/json")
-
-	response, err := apiClient.HTTPClient.Do(request)
-	if err != nil {
-		return nil, microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to invoke API: %w", err))
-	}
-
-	defer response.Body.Close()
-	if response.StatusCode > 300 { // All 3xx, 4xx, 5xx are considered errors
-		responseBodyString := ""
-		if responseBodyBytes, err := ioutil.ReadAll(response.Body); err != nil {
-			responseBodyString = string(responseBodyBytes)
-		}
-		return nil, microappError.NewAPICallError(apiURL, &response.StatusCode, &responseBodyString, fmt.Errorf("Received non-success code: %v", response.StatusCode))
-	}
-
-	var mapResponse interface{}
-	err = json.NewDecoder(response.Body).Decode(&mapResponse)
-	if err != nil {
-		return nil, microappError.NewAPICallError(apiURL, &response.StatusCode, nil, fmt.Errorf("Unable parse response payload: %w", err))
-	}
-
-	return mapResponse, nil
+	return apiClient.doRequestCtx(stdcontext.Background(), context, url, requestMethod, rawToken, payload)
 }
 
 // DoGet is a generic method to carry out RESTful calls to the other external microservices in ISLA
 func (apiClient *APIClient) DoGet(context microappCtx.ExecutionContext, requestString string, rawToken string) (map[string]interface{}, error) {
-	response, err := apiClient.doRequest(context, requestString, http.MethodGet, rawToken, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	mapResponse, ok := response.(map[string]interface{})
-	if !ok {
-		return nil, errors.New("Could not parse Json to map")
-	}
-	return mapResponse, nil
+	return apiClient.DoGetCtx(stdcontext.Background(), context, requestString, rawToken)
 }
 
 // DoGetList is a generic method to carry out RESTful calls to the other external microservices in ISLA
@@ -160,23 +104,10 @@ func (apiClient *APIClient) DoGetList(context microappCtx.ExecutionContext, requ
 
 // DoPost is a generic method to carry out RESTful calls to the other external microservices in ISLA
 func (apiClient *APIClient) DoPost(context microappCtx.ExecutionContext, requestString string, rawToken string, payload map[string]interface{}) (map[string]interface{}, error) {
-	response, err := apiClient.doRequest(context, requestString, http.MethodPost, rawToken, payload)
-	if err != nil {
-		return nil, err
-	}
-
-	mapResponse, ok := response.(map[string]interface{})
-	if !ok {
-		return nil, errors.New("Could not parse Json to map")
-	}
-	return mapResponse, nil
+	return apiClient.DoPostCtx(stdcontext.Background(), context, requestString, rawToken, payload)
 }
 
 // DoDelete is a generic method to carry out RESTful calls to the other external microservices in ISLA
 func (apiClient *APIClient) DoDelete(context microappCtx.ExecutionContext, requestString string, rawToken string, payload map[string]interface{}) error {
-	_, err := apiClient.doRequest(context, requestString, http.MethodDelete, rawToken, payload)
-	if err != nil {
-		return err
-	}
-	return nil
+	return apiClient.DoDeleteCtx(stdcontext.Background(), context, requestString, rawToken, payload)
 }
@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals a request payload and unmarshals a response body for a
+// particular wire format, and advertises the Content-Type that format is
+// sent/accepted as.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the client's historical wire format, and the default used
+// when APIClient.Codec is nil.
+type JSONCodec struct{}
+
+// ContentType returns "application/json".
+func (JSONCodec) ContentType() string { return "application/json" }
+
+// Marshal encodes v as JSON.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON data into v.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// ProtoCodec encodes/decodes a protocol buffer message as application/x-protobuf.
+// v must implement proto.Message.
+type ProtoCodec struct{}
+
+// ContentType returns "application/x-protobuf".
+func (ProtoCodec) ContentType() string { return "application/x-protobuf" }
+
+// Marshal encodes v, which must implement proto.Message, as a protobuf wire message.
+func (ProtoCodec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("ProtoCodec: %T does not implement proto.Message", v)
+	}
+	return proto.Marshal(message)
+}
+
+// Unmarshal decodes a protobuf wire message into v, which must implement proto.Message.
+func (ProtoCodec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("ProtoCodec: %T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, message)
+}
+
+// MsgpackCodec encodes/decodes as application/x-msgpack.
+type MsgpackCodec struct{}
+
+// ContentType returns "application/x-msgpack".
+func (MsgpackCodec) ContentType() string { return "application/x-msgpack" }
+
+// Marshal encodes v as msgpack.
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) { return msgpack.Marshal(v) }
+
+// Unmarshal decodes msgpack data into v.
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+
+// codecsByContentType lets the client select an unmarshaler based on a
+// response's Content-Type, mirroring the go-micro handler pattern: a client
+// can send JSON but transparently decode, say, an application/x-protobuf response.
+var codecsByContentType = map[string]Codec{
+	JSONCodec{}.ContentType():    JSONCodec{},
+	ProtoCodec{}.ContentType():   ProtoCodec{},
+	MsgpackCodec{}.ContentType(): MsgpackCodec{},
+}
+
+// codecForContentType resolves the Codec to use for decoding a response body
+// based on its Content-Type header, falling back to requestCodec (the codec
+// the request was sent with) when the header is absent or unrecognized.
+func codecForContentType(contentType string, requestCodec Codec) Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return requestCodec
+	}
+	if codec, ok := codecsByContentType[mediaType]; ok {
+		return codec
+	}
+	return requestCodec
+}
+
+// resolveCodec returns apiClient.Codec, defaulting to JSONCodec to preserve
+// the client's historical wire format when none is configured.
+func (apiClient *APIClient) resolveCodec() Codec {
+	if apiClient.Codec != nil {
+		return apiClient.Codec
+	}
+	return JSONCodec{}
+}
@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestJSONCodecRoundTrips(t *testing.T) {
+	codec := JSONCodec{}
+	if got := codec.ContentType(); got != "application/json" {
+		t.Errorf("ContentType() = %q, want %q", got, "application/json")
+	}
+
+	data, err := codec.Marshal(map[string]interface{}{"name": "widget"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var out map[string]interface{}
+	if err := codec.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out["name"] != "widget" {
+		t.Errorf("Unmarshal() = %v, want name:widget", out)
+	}
+}
+
+func TestMsgpackCodecRoundTrips(t *testing.T) {
+	codec := MsgpackCodec{}
+	if got := codec.ContentType(); got != "application/x-msgpack" {
+		t.Errorf("ContentType() = %q, want %q", got, "application/x-msgpack")
+	}
+
+	data, err := codec.Marshal(map[string]interface{}{"name": "widget"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var out map[string]interface{}
+	if err := codec.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out["name"] != "widget" {
+		t.Errorf("Unmarshal() = %v, want name:widget", out)
+	}
+}
+
+func TestProtoCodecRoundTrips(t *testing.T) {
+	codec := ProtoCodec{}
+	if got := codec.ContentType(); got != "application/x-protobuf" {
+		t.Errorf("ContentType() = %q, want %q", got, "application/x-protobuf")
+	}
+
+	data, err := codec.Marshal(wrapperspb.String("widget"))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	out := &wrapperspb.StringValue{}
+	if err := codec.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.Value != "widget" {
+		t.Errorf("Unmarshal() = %q, want %q", out.Value, "widget")
+	}
+}
+
+func TestProtoCodecRejectsNonProtoMessage(t *testing.T) {
+	codec := ProtoCodec{}
+	if _, err := codec.Marshal(map[string]interface{}{"name": "widget"}); err == nil {
+		t.Error("Marshal() error = nil, want an error: a map does not implement proto.Message")
+	}
+	if err := codec.Unmarshal([]byte{}, &map[string]interface{}{}); err == nil {
+		t.Error("Unmarshal() error = nil, want an error: a map does not implement proto.Message")
+	}
+}
+
+func TestCodecForContentTypeNegotiatesKnownTypes(t *testing.T) {
+	cases := []struct {
+		contentType string
+		want        Codec
+	}{
+		{"application/json", JSONCodec{}},
+		{"application/json; charset=utf-8", JSONCodec{}},
+		{"application/x-protobuf", ProtoCodec{}},
+		{"application/x-msgpack", MsgpackCodec{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.contentType, func(t *testing.T) {
+			if got := codecForContentType(tc.contentType, JSONCodec{}); got != tc.want {
+				t.Errorf("codecForContentType(%q) = %#v, want %#v", tc.contentType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCodecForContentTypeFallsBackToRequestCodec(t *testing.T) {
+	fallback := MsgpackCodec{}
+	cases := []string{"", "text/plain", "not a content type; =="}
+	for _, contentType := range cases {
+		if got := codecForContentType(contentType, fallback); got != fallback {
+			t.Errorf("codecForContentType(%q, fallback) = %#v, want fallback %#v", contentType, got, fallback)
+		}
+	}
+}
+
+func TestResolveCodecDefaultsToJSON(t *testing.T) {
+	apiClient := &APIClient{}
+	if got := apiClient.resolveCodec(); got != (JSONCodec{}) {
+		t.Errorf("resolveCodec() = %#v, want JSONCodec{}", got)
+	}
+
+	apiClient.Codec = MsgpackCodec{}
+	if got := apiClient.resolveCodec(); got != (MsgpackCodec{}) {
+		t.Errorf("resolveCodec() = %#v, want the configured Codec", got)
+	}
+}
@@ -0,0 +1,156 @@
+package clients
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	microappCtx "github.com/islax/microapp/context"
+	microappError "github.com/islax/microapp/error"
+)
+
+// WithTimeout wraps ctx with a timeout, returning a derived context and its
+// CancelFunc so a caller can bound a single Do*Ctx call without building its
+// own context tree. The caller must invoke the returned CancelFunc once the
+// call completes, typically with defer.
+func WithTimeout(ctx stdcontext.Context, d time.Duration) (stdcontext.Context, stdcontext.CancelFunc) {
+	return stdcontext.WithTimeout(ctx, d)
+}
+
+// WithDeadline wraps ctx with an absolute deadline, returning a derived
+// context and its CancelFunc. The caller must invoke the returned CancelFunc
+// once the call completes, typically with defer.
+func WithDeadline(ctx stdcontext.Context, t time.Time) (stdcontext.Context, stdcontext.CancelFunc) {
+	return stdcontext.WithDeadline(ctx, t)
+}
+
+// DoRequestWithResponseParamCtx is DoRequestWithResponseParam with an explicit context.Context
+// so a caller can cancel the call or bound it with a deadline via WithTimeout/WithDeadline.
+func (apiClient *APIClient) DoRequestWithResponseParamCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload map[string]interface{}, out interface{}) error {
+	var p interface{}
+	if payload != nil {
+		p = payload
+	}
+	return apiClient.doRequestWithCodecCtx(ctx, execCtx, url, requestMethod, rawToken, p, out, apiClient.resolveCodec())
+}
+
+// DoRequestWithCodec is DoRequestWithResponseParamCtx with an explicit Codec, so a
+// single call can use a wire format other than apiClient.Codec (e.g. a
+// proto.Message payload sent with ProtoCodec) without changing the client's default.
+func (apiClient *APIClient) DoRequestWithCodec(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload interface{}, out interface{}, codec Codec) error {
+	return apiClient.doRequestWithCodecCtx(ctx, execCtx, url, requestMethod, rawToken, payload, out, codec)
+}
+
+func (apiClient *APIClient) doRequestWithCodecCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload interface{}, out interface{}, codec Codec) error {
+	apiURL := apiClient.BaseURL + url
+	var bytePayload []byte
+	if payload != nil {
+		bp, err := codec.Marshal(payload)
+		if err != nil {
+			return microappError.NewAPICallError(apiURL, nil, nil, fmt.Errorf("Unable to marshal payload: %w", err))
+		}
+		bytePayload = bp
+	}
+
+	response, attempts, err := apiClient.executeWithRetry(ctx, execCtx, requestMethod, apiURL, rawToken, bytePayload, codec)
+	if err != nil {
+		return microappError.NewAPICallError(apiURL, statusCodeOf(err), bodyOf(err), translateChainError(ctx, err, attempts))
+	}
+
+	defer response.Body.Close()
+	if out != nil {
+		responseBody, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return microappError.NewAPICallError(apiURL, &response.StatusCode, nil, fmt.Errorf("Unable to read response payload: %w", err))
+		}
+		responseCodec := codecForContentType(response.Header.Get("Content-Type"), codec)
+		if err := responseCodec.Unmarshal(responseBody, out); err != nil {
+			return microappError.NewAPICallError(apiURL, &response.StatusCode, nil, fmt.Errorf("Unable parse response payload: %w", err))
+		}
+	}
+	return nil
+}
+
+func (apiClient *APIClient) doRequestCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, url string, requestMethod string, rawToken string, payload map[string]interface{}) (interface{}, error) {
+	var p interface{}
+	if payload != nil {
+		p = payload
+	}
+	var mapResponse interface{}
+	if err := apiClient.doRequestWithCodecCtx(ctx, execCtx, url, requestMethod, rawToken, p, &mapResponse, apiClient.resolveCodec()); err != nil {
+		return nil, err
+	}
+	return mapResponse, nil
+}
+
+// statusCodeOf and bodyOf extract the fields NewAPICallError expects from a
+// responseStatusError produced by the default ValidateResponse interceptor,
+// or nil when err came from somewhere else in the chain (e.g. the transport).
+func statusCodeOf(err error) *int {
+	var statusErr *responseStatusError
+	if errors.As(err, &statusErr) {
+		return &statusErr.StatusCode
+	}
+	return nil
+}
+
+func bodyOf(err error) *string {
+	var statusErr *responseStatusError
+	if errors.As(err, &statusErr) {
+		return &statusErr.Body
+	}
+	return nil
+}
+
+// translateChainError produces the message NewAPICallError wraps: the
+// responseStatusError's own message for a validation failure, or a
+// transport-failure message (classified against ctx) for anything else. attempts
+// is folded into the message so a retried call's error records how many
+// attempts were made.
+func translateChainError(ctx stdcontext.Context, err error, attempts int) error {
+	var statusErr *responseStatusError
+	if errors.As(err, &statusErr) {
+		return fmt.Errorf("%w (after %d attempt(s))", statusErr, attempts)
+	}
+	return fmt.Errorf("Unable to invoke API after %d attempt(s): %w", attempts, classifyTransportError(ctx, err))
+}
+
+// DoGetCtx is DoGet with an explicit context.Context so a caller can cancel the call
+// or bound it with a deadline via WithTimeout/WithDeadline.
+func (apiClient *APIClient) DoGetCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, requestString string, rawToken string) (map[string]interface{}, error) {
+	response, err := apiClient.doRequestCtx(ctx, execCtx, requestString, http.MethodGet, rawToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mapResponse, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Could not parse Json to map")
+	}
+	return mapResponse, nil
+}
+
+// DoPostCtx is DoPost with an explicit context.Context so a caller can cancel the call
+// or bound it with a deadline via WithTimeout/WithDeadline.
+func (apiClient *APIClient) DoPostCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, requestString string, rawToken string, payload map[string]interface{}) (map[string]interface{}, error) {
+	response, err := apiClient.doRequestCtx(ctx, execCtx, requestString, http.MethodPost, rawToken, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	mapResponse, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Could not parse Json to map")
+	}
+	return mapResponse, nil
+}
+
+// DoDeleteCtx is DoDelete with an explicit context.Context so a caller can cancel the call
+// or bound it with a deadline via WithTimeout/WithDeadline.
+func (apiClient *APIClient) DoDeleteCtx(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, requestString string, rawToken string, payload map[string]interface{}) error {
+	_, err := apiClient.doRequestCtx(ctx, execCtx, requestString, http.MethodDelete, rawToken, payload)
+	return err
+}
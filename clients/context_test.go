@@ -0,0 +1,82 @@
+package clients
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutProducesDeadlineExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	ctx, cancel := WithTimeout(stdcontext.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := apiClient.DoGetCtx(ctx, testExecutionContext{}, "/widgets", "")
+	if err == nil {
+		t.Fatal("DoGetCtx() error = nil, want a deadline-exceeded error")
+	}
+	if !errors.Is(err, stdcontext.DeadlineExceeded) {
+		t.Errorf("DoGetCtx() error = %v, want errors.Is(err, context.DeadlineExceeded)", err)
+	}
+}
+
+func TestWithDeadlineProducesDeadlineExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	ctx, cancel := WithDeadline(stdcontext.Background(), time.Now().Add(time.Millisecond))
+	defer cancel()
+
+	_, err := apiClient.DoGetCtx(ctx, testExecutionContext{}, "/widgets", "")
+	if err == nil {
+		t.Fatal("DoGetCtx() error = nil, want a deadline-exceeded error")
+	}
+	if !errors.Is(err, stdcontext.DeadlineExceeded) {
+		t.Errorf("DoGetCtx() error = %v, want errors.Is(err, context.DeadlineExceeded)", err)
+	}
+}
+
+func TestDoGetCtxProducesCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	time.AfterFunc(time.Millisecond, cancel)
+
+	_, err := apiClient.DoGetCtx(ctx, testExecutionContext{}, "/widgets", "")
+	if err == nil {
+		t.Fatal("DoGetCtx() error = nil, want a canceled error")
+	}
+	if !errors.Is(err, stdcontext.Canceled) {
+		t.Errorf("DoGetCtx() error = %v, want errors.Is(err, context.Canceled)", err)
+	}
+}
+
+func TestDoGetCtxDoesNotMisclassifyATransportFailureAsContextError(t *testing.T) {
+	apiClient := &APIClient{BaseURL: "http://127.0.0.1:0", HTTPClient: http.DefaultClient}
+
+	_, err := apiClient.DoGetCtx(stdcontext.Background(), testExecutionContext{}, "/widgets", "")
+	if err == nil {
+		t.Fatal("DoGetCtx() error = nil, want a connection error")
+	}
+	if errors.Is(err, stdcontext.DeadlineExceeded) || errors.Is(err, stdcontext.Canceled) {
+		t.Errorf("DoGetCtx() error = %v, want a plain transport error, not classified as a context error", err)
+	}
+}
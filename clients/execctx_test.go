@@ -0,0 +1,7 @@
+package clients
+
+// testExecutionContext is a minimal microappCtx.ExecutionContext stub shared
+// by this package's tests, which only ever need a correlation ID.
+type testExecutionContext struct{}
+
+func (testExecutionContext) GetCorrelationID() string { return "test-correlation-id" }
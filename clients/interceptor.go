@@ -0,0 +1,152 @@
+package clients
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	microappCtx "github.com/islax/microapp/context"
+)
+
+// RoundTripFunc executes a single HTTP round trip for a fully-built request.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Interceptor wraps a RoundTripFunc with additional behavior, e.g. retries,
+// circuit breaking, metrics, or auth-token refresh. An interceptor can run
+// logic both before it calls next (on the outgoing request) and after next
+// returns (on the response or error), the same shape as an AWS SDK named
+// handler or a gRPC interceptor.
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// responseStatusError carries the status code and body of a non-2xx response
+// through the interceptor chain so doRequest/doRequestCtx can translate it
+// into a microappError.APICallError without ValidateResponse interceptors
+// needing to know about that type.
+type responseStatusError struct {
+	StatusCode int
+	Body       string
+	Header     http.Header
+}
+
+func (e *responseStatusError) Error() string {
+	return fmt.Sprintf("Received non-success code: %v", e.StatusCode)
+}
+
+// Use registers one or more general-purpose interceptors that run after Sign
+// and before ValidateResponse, e.g. a retry interceptor with exponential
+// backoff on 5xx/429, a Prometheus interceptor observing status code and
+// latency, or a token-refresh interceptor that re-signs after a 401.
+func (apiClient *APIClient) Use(interceptors ...Interceptor) {
+	apiClient.interceptors = append(apiClient.interceptors, interceptors...)
+}
+
+func applyPhase(phase []Interceptor, rt RoundTripFunc) RoundTripFunc {
+	for i := len(phase) - 1; i >= 0; i-- {
+		rt = phase[i](rt)
+	}
+	return rt
+}
+
+// chain composes the Build, Sign, user-registered, ValidateResponse and
+// Unmarshal phases (outermost to innermost, in that order) around the actual
+// HTTP round trip, wrapped by any Send interceptors. contentType is the wire
+// format the request is sent (and, by default, expected to be returned) as.
+func (apiClient *APIClient) chain(execCtx microappCtx.ExecutionContext, rawToken string, contentType string) RoundTripFunc {
+	rt := apiClient.chainAfterBuild(rawToken)
+	rt = applyPhase(apiClient.Build, rt)
+	rt = defaultBuildInterceptor(apiClient.AppName, execCtx, contentType)(rt)
+	return rt
+}
+
+// chainAfterBuild composes the Sign, user-registered, ValidateResponse and
+// Unmarshal phases around apiClient.HTTPClient.Do, wrapped by any Send
+// interceptors, i.e. everything chain applies after its Build phase. A
+// caller that sets request headers itself instead of going through
+// defaultBuildInterceptor (e.g. DoMultipart, which negotiates a different
+// Content-Type and Accept) uses chainAfterBuildAround, the same composition
+// with its own terminal round trip, so those headers survive.
+func (apiClient *APIClient) chainAfterBuild(rawToken string) RoundTripFunc {
+	return apiClient.chainAfterBuildAround(rawToken, func(request *http.Request) (*http.Response, error) {
+		return apiClient.HTTPClient.Do(request)
+	})
+}
+
+// chainAfterBuildAround is chainAfterBuild with the innermost round trip
+// replaced by terminal, still wrapped by any Send interceptors. A caller
+// that must not commit to an action (e.g. DoMultipart, whose request body is
+// a pipe nobody has started writing to yet) uses this to defer that action
+// until nothing earlier in the chain can fail first.
+func (apiClient *APIClient) chainAfterBuildAround(rawToken string, terminal RoundTripFunc) RoundTripFunc {
+	terminal = applyPhase(apiClient.Send, terminal)
+
+	rt := terminal
+	rt = applyPhase(apiClient.Unmarshal, rt)
+	rt = applyPhase(apiClient.ValidateResponse, rt)
+	rt = defaultValidateResponseInterceptor(rt)
+	rt = applyPhase(apiClient.interceptors, rt)
+	rt = applyPhase(apiClient.Sign, rt)
+	rt = defaultSignInterceptor(rawToken, apiClient.TokenProvider)(rt)
+	return rt
+}
+
+// defaultBuildInterceptor sets the headers that identify the caller and
+// negotiate the request/response body format; this is the default behavior
+// every request has always had.
+func defaultBuildInterceptor(appName string, execCtx microappCtx.ExecutionContext, contentType string) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(request *http.Request) (*http.Response, error) {
+			request.Header.Set("X-Client", appName)
+			request.Header.Set("X-Correlation-ID", execCtx.GetCorrelationID())
+			request.Header.Set("Content-Type", contentType)
+			request.Header.Set("Accept", contentType)
+			return next(request)
+		}
+	}
+}
+
+// defaultSignInterceptor sets the Authorization header from rawToken, falling
+// back to tokenProvider when rawToken is empty so a caller that configured
+// APIClient.TokenProvider can omit it.
+func defaultSignInterceptor(rawToken string, tokenProvider TokenProvider) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(request *http.Request) (*http.Response, error) {
+			token := rawToken
+			if token == "" && tokenProvider != nil {
+				fetched, err := tokenProvider.Token(request.Context())
+				if err != nil {
+					return nil, fmt.Errorf("Unable to obtain token: %w", err)
+				}
+				token = fetched
+			}
+			if token != "" {
+				if strings.HasPrefix(token, "Bearer") {
+					request.Header.Add("Authorization", token)
+				} else {
+					request.Header.Add("Authorization", "Bearer "+token)
+				}
+			}
+			return next(request)
+		}
+	}
+}
+
+// defaultValidateResponseInterceptor turns a non-2xx response into a
+// responseStatusError; this is the default behavior every request has always had.
+func defaultValidateResponseInterceptor(next RoundTripFunc) RoundTripFunc {
+	return func(request *http.Request) (*http.Response, error) {
+		response, err := next(request)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode > 300 { // All 3xx, 4xx, 5xx are considered errors
+			responseBodyString := ""
+			if responseBodyBytes, err := ioutil.ReadAll(response.Body); err == nil {
+				responseBodyString = string(responseBodyBytes)
+			}
+			response.Body.Close()
+			return nil, &responseStatusError{StatusCode: response.StatusCode, Body: responseBodyString, Header: response.Header}
+		}
+		return response, nil
+	}
+}
@@ -0,0 +1,38 @@
+package clients
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	microappError "github.com/islax/microapp/error"
+)
+
+func TestNonSuccessResponseBodyIsCapturedThroughChain(t *testing.T) {
+	const body = `{"error":"boom"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	_, err := apiClient.DoGetCtx(stdcontext.Background(), testExecutionContext{}, "/widgets", "")
+	if err == nil {
+		t.Fatal("DoGetCtx() error = nil, want a non-2xx error")
+	}
+
+	var apiErr *microappError.APICallError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("DoGetCtx() error = %v, want a *microappError.APICallError", err)
+	}
+	if apiErr.StatusCode == nil || *apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("APICallError.StatusCode = %v, want %d", apiErr.StatusCode, http.StatusInternalServerError)
+	}
+	if apiErr.Body == nil || *apiErr.Body != body {
+		t.Errorf("APICallError.Body = %v, want %q: the non-2xx response body must survive the ValidateResponse interceptor, not be swallowed", apiErr.Body, body)
+	}
+}
@@ -0,0 +1,140 @@
+package clients
+
+import (
+	stdcontext "context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoMultipartSendsFieldsAndFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+			t.Errorf("Content-Type = %q, want multipart/form-data", r.Header.Get("Content-Type"))
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		form, err := reader.ReadForm(1 << 20)
+		if err != nil {
+			t.Fatalf("ReadForm() error = %v", err)
+		}
+		if got := form.Value["name"][0]; got != "widget" {
+			t.Errorf("field %q = %q, want %q", "name", got, "widget")
+		}
+		file := form.File["file"][0]
+		if file.Filename != "widget.txt" {
+			t.Errorf("Filename = %q, want %q", file.Filename, "widget.txt")
+		}
+		opened, _ := file.Open()
+		content, _ := io.ReadAll(opened)
+		if string(content) != "widget contents" {
+			t.Errorf("file content = %q, want %q", content, "widget contents")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	response, err := apiClient.DoMultipart(stdcontext.Background(), testExecutionContext{}, "/widgets", "", map[string]string{"name": "widget"}, []FileUpload{
+		{FieldName: "file", Filename: "widget.txt", Content: strings.NewReader("widget contents")},
+	})
+	if err != nil {
+		t.Fatalf("DoMultipart() error = %v", err)
+	}
+	if response["id"] != "1" {
+		t.Errorf("response = %v, want id:1", response)
+	}
+}
+
+func TestDoMultipartRunsGeneralInterceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var observed int
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	apiClient.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(request *http.Request) (*http.Response, error) {
+			observed++
+			return next(request)
+		}
+	})
+
+	if _, err := apiClient.DoMultipart(stdcontext.Background(), testExecutionContext{}, "/widgets", "", nil, nil); err != nil {
+		t.Fatalf("DoMultipart() error = %v", err)
+	}
+	if observed != 1 {
+		t.Errorf("general interceptor ran %d times, want 1", observed)
+	}
+}
+
+var errTokenFetch = errors.New("token fetch failed")
+
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx stdcontext.Context) (string, error) { return "", errTokenFetch }
+func (failingTokenProvider) Invalidate()                                  {}
+
+func TestDoMultipartDoesNotBlockWhenSigningFails(t *testing.T) {
+	apiClient := &APIClient{BaseURL: "http://example.invalid", HTTPClient: http.DefaultClient, TokenProvider: failingTokenProvider{}}
+	assertDoMultipartDoesNotLeakGoroutine(t, apiClient)
+}
+
+func TestDoMultipartDoesNotBlockWhenAUseInterceptorFails(t *testing.T) {
+	errUseInterceptor := errors.New("use interceptor failed")
+	apiClient := &APIClient{BaseURL: "http://example.invalid", HTTPClient: http.DefaultClient}
+	apiClient.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(request *http.Request) (*http.Response, error) {
+			return nil, errUseInterceptor
+		}
+	})
+	assertDoMultipartDoesNotLeakGoroutine(t, apiClient)
+}
+
+// assertDoMultipartDoesNotLeakGoroutine calls DoMultipart on apiClient, which
+// must fail before it ever sends the request, and checks both that the call
+// returns promptly and that the goroutine count settles back to its baseline
+// afterwards, i.e. the body-writer goroutine was never started rather than
+// merely unblocked some other way.
+func assertDoMultipartDoesNotLeakGoroutine(t *testing.T, apiClient *APIClient) {
+	t.Helper()
+	runtime.GC()
+	before := runtime.NumGoroutine()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := apiClient.DoMultipart(stdcontext.Background(), testExecutionContext{}, "/widgets", "", nil, []FileUpload{
+			{FieldName: "file", Filename: "f.txt", Content: strings.NewReader("content")},
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("DoMultipart() error = nil, want a pre-Send failure")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("DoMultipart() did not return: the body-writer goroutine is blocked on a pipe nobody reads")
+	}
+
+	for i := 0; i < 20; i++ {
+		runtime.GC()
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("goroutine count after DoMultipart() = %d, want <= %d (baseline): the body-writer goroutine leaked", runtime.NumGoroutine(), before)
+}
@@ -0,0 +1,248 @@
+package clients
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	microappCtx "github.com/islax/microapp/context"
+)
+
+// APIListObject mirrors the limit/offset/more/total envelope PagerDuty's list
+// endpoints return alongside the endpoint-specific items array.
+type APIListObject struct {
+	Limit  int  `json:"limit"`
+	Offset int  `json:"offset"`
+	More   bool `json:"more"`
+	Total  int  `json:"total"`
+}
+
+// decodeListObject re-decodes response's limit/offset/more/total fields into
+// an APIListObject, ignoring the endpoint-specific items array alongside them.
+func decodeListObject(response map[string]interface{}) (APIListObject, error) {
+	var listObject APIListObject
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return listObject, fmt.Errorf("Paginator: unable to re-marshal response: %w", err)
+	}
+	if err := json.Unmarshal(raw, &listObject); err != nil {
+		return listObject, fmt.Errorf("Paginator: unable to decode list envelope: %w", err)
+	}
+	return listObject, nil
+}
+
+// PaginatorOptions configures a Paginator's walk over a paginated list endpoint.
+type PaginatorOptions struct {
+	// ExecutionContext is threaded into every page request for correlation-ID propagation.
+	ExecutionContext microappCtx.ExecutionContext
+	// ItemsKey is the key the endpoint returns its array of items under,
+	// e.g. "incidents" for a PagerDuty-style envelope.
+	ItemsKey string
+	// PageSize is the number of items requested per page. Defaults to 25 if zero.
+	PageSize int
+	// OffsetParam and LimitParam are the query parameters offset/limit mode
+	// sends the current offset and PageSize as. Default to "offset" and "limit".
+	OffsetParam string
+	LimitParam  string
+	// CursorParam is the query parameter the next page's cursor is sent as.
+	// A non-empty CursorParam selects cursor-style pagination over offset/limit.
+	CursorParam string
+	// CursorPath is the dot-separated path to the next cursor value within a
+	// page's raw response, e.g. "next_cursor" or "meta.next_cursor".
+	CursorPath string
+}
+
+func (opts PaginatorOptions) pageSize() int {
+	if opts.PageSize <= 0 {
+		return 25
+	}
+	return opts.PageSize
+}
+
+func (opts PaginatorOptions) offsetParam() string {
+	if opts.OffsetParam == "" {
+		return "offset"
+	}
+	return opts.OffsetParam
+}
+
+func (opts PaginatorOptions) limitParam() string {
+	if opts.LimitParam == "" {
+		return "limit"
+	}
+	return opts.LimitParam
+}
+
+func (opts PaginatorOptions) cursorMode() bool {
+	return opts.CursorParam != ""
+}
+
+// Paginator walks a paginated list endpoint one page at a time, in either
+// offset/limit or cursor style depending on how it was configured.
+type Paginator struct {
+	apiClient  *APIClient
+	requestURL string
+	token      string
+	opts       PaginatorOptions
+
+	offset int
+	cursor string
+	done   bool
+	page   []map[string]interface{}
+	err    error
+}
+
+// NewPaginator returns a Paginator over requestURL, starting from the first page.
+func (apiClient *APIClient) NewPaginator(ctx stdcontext.Context, requestURL string, token string, opts PaginatorOptions) *Paginator {
+	return &Paginator{
+		apiClient:  apiClient,
+		requestURL: requestURL,
+		token:      token,
+		opts:       opts,
+	}
+}
+
+// Next fetches the next page, returning false once there are no more pages or
+// a request fails. Check Err after Next returns false to distinguish the two.
+func (p *Paginator) Next(ctx stdcontext.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	pageURL, err := p.buildPageURL()
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	response, err := p.apiClient.DoGetCtx(ctx, p.opts.ExecutionContext, pageURL, p.token)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	items, err := itemsAt(response, p.opts.ItemsKey)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+	p.page = items
+
+	if len(items) == 0 {
+		p.done = true
+		return false
+	}
+
+	if p.opts.cursorMode() {
+		cursor, ok := extractAtPath(response, p.opts.CursorPath)
+		cursorString, _ := cursor.(string)
+		if !ok || cursorString == "" {
+			p.done = true
+		} else {
+			p.cursor = cursorString
+		}
+	} else {
+		listObject, err := decodeListObject(response)
+		if err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+		p.offset += len(items)
+		if !listObject.More || (listObject.Total > 0 && p.offset >= listObject.Total) {
+			p.done = true
+		}
+	}
+	return true
+}
+
+// Page returns the items fetched by the most recent call to Next.
+func (p *Paginator) Page() []map[string]interface{} {
+	return p.page
+}
+
+// Err returns the error that stopped iteration, or nil if it ran to completion.
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+// EachItem walks every page, invoking fn for each item and stopping at the
+// first error fn returns, or the first error encountered fetching a page.
+func (p *Paginator) EachItem(ctx stdcontext.Context, fn func(item map[string]interface{}) error) error {
+	for p.Next(ctx) {
+		for _, item := range p.Page() {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+	return p.Err()
+}
+
+func (p *Paginator) buildPageURL() (string, error) {
+	parsed, err := url.Parse(p.requestURL)
+	if err != nil {
+		return "", fmt.Errorf("Unable to parse paginator URL: %w", err)
+	}
+	query := parsed.Query()
+	if p.opts.cursorMode() {
+		// The first page is fetched with no cursor; Next stops iteration (via
+		// p.done) as soon as a page carries no next cursor, so buildPageURL is
+		// never called again with an empty p.cursor after the first page.
+		if p.cursor != "" {
+			query.Set(p.opts.CursorParam, p.cursor)
+		}
+	} else {
+		query.Set(p.opts.offsetParam(), strconv.Itoa(p.offset))
+		query.Set(p.opts.limitParam(), strconv.Itoa(p.opts.pageSize()))
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// itemsAt extracts response[itemsKey] as a []map[string]interface{}.
+func itemsAt(response map[string]interface{}, itemsKey string) ([]map[string]interface{}, error) {
+	rawItems, ok := response[itemsKey]
+	if !ok {
+		return nil, fmt.Errorf("Paginator: response has no %q key", itemsKey)
+	}
+	sliceOfGenericObjects, ok := rawItems.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Paginator: %q is not an array", itemsKey)
+	}
+	items := make([]map[string]interface{}, 0, len(sliceOfGenericObjects))
+	for _, obj := range sliceOfGenericObjects {
+		mapObject, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Paginator: item in %q is not an object", itemsKey)
+		}
+		items = append(items, mapObject)
+	}
+	return items, nil
+}
+
+// extractAtPath walks data following a dot-separated path of map keys,
+// returning the value found there and whether the whole path resolved.
+func extractAtPath(data map[string]interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
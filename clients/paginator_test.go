@@ -0,0 +1,106 @@
+package clients
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestPaginatorOffsetModeStopsAtTotal(t *testing.T) {
+	const total = 5
+	var requestedOffsets []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := 0
+		if v := r.URL.Query().Get("offset"); v != "" {
+			offset, _ = strconv.Atoi(v)
+		}
+		requestedOffsets = append(requestedOffsets, offset)
+
+		remaining := total - offset
+		if remaining > 2 {
+			remaining = 2
+		}
+		items := make([]map[string]interface{}, remaining)
+		for i := range items {
+			items[i] = map[string]interface{}{"id": offset + i}
+		}
+		// More is left true even past the last page: Next must stop on the
+		// Total bookkeeping rather than trusting this forever.
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"incidents": items,
+			"limit":     2,
+			"offset":    offset,
+			"more":      true,
+			"total":     total,
+		})
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	paginator := apiClient.NewPaginator(stdcontext.Background(), "/incidents", "", PaginatorOptions{
+		ExecutionContext: testExecutionContext{},
+		ItemsKey:         "incidents",
+		PageSize:         2,
+	})
+
+	var seen int
+	for paginator.Next(stdcontext.Background()) {
+		seen += len(paginator.Page())
+	}
+	if err := paginator.Err(); err != nil {
+		t.Fatalf("Paginator.Err() = %v, want nil", err)
+	}
+	if seen != total {
+		t.Errorf("paginated %d items, want %d", seen, total)
+	}
+	if len(requestedOffsets) != 3 {
+		t.Errorf("made %d page requests, want 3 (stop once Total items are seen)", len(requestedOffsets))
+	}
+}
+
+func TestPaginatorCursorModeStopsOnEmptyCursor(t *testing.T) {
+	pages := []struct {
+		items      []map[string]interface{}
+		nextCursor string
+	}{
+		{items: []map[string]interface{}{{"id": 1}, {"id": 2}}, nextCursor: "page2"},
+		{items: []map[string]interface{}{{"id": 3}}, nextCursor: ""},
+	}
+	var call int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := pages[call]
+		call++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"incidents":   page.items,
+			"next_cursor": page.nextCursor,
+		})
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	paginator := apiClient.NewPaginator(stdcontext.Background(), "/incidents", "", PaginatorOptions{
+		ExecutionContext: testExecutionContext{},
+		ItemsKey:         "incidents",
+		CursorParam:      "cursor",
+		CursorPath:       "next_cursor",
+	})
+
+	var seen int
+	for paginator.Next(stdcontext.Background()) {
+		seen += len(paginator.Page())
+	}
+	if err := paginator.Err(); err != nil {
+		t.Fatalf("Paginator.Err() = %v, want nil", err)
+	}
+	if seen != 3 {
+		t.Errorf("paginated %d items, want 3", seen)
+	}
+	if call != 2 {
+		t.Errorf("made %d page requests, want 2 (stop once next_cursor is empty)", call)
+	}
+}
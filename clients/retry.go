@@ -0,0 +1,159 @@
+package clients
+
+import (
+	"bytes"
+	stdcontext "context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	microappCtx "github.com/islax/microapp/context"
+)
+
+// RetryPolicy controls automatic retry of a request on a retryable failure.
+// A nil RetryPolicy disables retries, which is the client's historical
+// one-shot behavior and remains the default.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first; a
+	// policy with MaxAttempts <= 1 never retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each
+	// subsequent attempt. Defaults to 100ms if zero.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay before jitter is applied.
+	// Defaults to 30s if zero.
+	MaxDelay time.Duration
+	// RetryableStatuses are the HTTP status codes that should be retried,
+	// e.g. {429: true, 502: true, 503: true, 504: true}.
+	RetryableStatuses map[int]bool
+	// RetryableErrors reports whether a non-status error (e.g. a connection
+	// reset) should be retried. If nil, no non-status error is retried.
+	RetryableErrors func(error) bool
+}
+
+func (policy *RetryPolicy) maxAttempts() int {
+	if policy == nil || policy.MaxAttempts < 1 {
+		return 1
+	}
+	return policy.MaxAttempts
+}
+
+// shouldRetry reports whether err is retryable under policy: a response
+// status in RetryableStatuses, or a non-status error accepted by RetryableErrors.
+func (policy *RetryPolicy) shouldRetry(err error) bool {
+	if policy == nil {
+		return false
+	}
+	var statusErr *responseStatusError
+	if errors.As(err, &statusErr) {
+		return policy.RetryableStatuses[statusErr.StatusCode]
+	}
+	if policy.RetryableErrors != nil {
+		return policy.RetryableErrors(err)
+	}
+	return false
+}
+
+// backoff computes the full-jitter delay before the given 1-based retry
+// attempt (the delay before the first retry is attempt 1), honoring a
+// Retry-After duration extracted from the previous response when present
+// instead of the computed exponential backoff.
+func (policy *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	base := policy.BaseDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// executeWithRetry runs request through apiClient's interceptor chain,
+// retrying on a retryable failure per apiClient.RetryPolicy. bytePayload is
+// the already-marshaled request body (or nil); it is re-read into a fresh
+// io.Reader on every attempt so a buffered body can be resent. codec is the
+// wire format the payload was marshaled with, used to negotiate Content-Type
+// and Accept. It returns the successful response, the number of attempts
+// made, and the last error.
+func (apiClient *APIClient) executeWithRetry(ctx stdcontext.Context, execCtx microappCtx.ExecutionContext, requestMethod string, apiURL string, rawToken string, bytePayload []byte, codec Codec) (*http.Response, int, error) {
+	rt := apiClient.chain(execCtx, rawToken, codec.ContentType())
+	maxAttempts := apiClient.RetryPolicy.maxAttempts()
+
+	var lastErr error
+	usedAuthRetry := false
+	totalAttempts := 0
+	for attempt := 1; attempt <= maxAttempts; {
+		totalAttempts++
+		var body io.Reader
+		if bytePayload != nil {
+			body = bytes.NewReader(bytePayload)
+		}
+		request, err := http.NewRequestWithContext(ctx, requestMethod, apiURL, body)
+		if err != nil {
+			return nil, totalAttempts, err
+		}
+
+		response, err := rt(request)
+		if err == nil {
+			return response, totalAttempts, nil
+		}
+		lastErr = err
+
+		var statusErr *responseStatusError
+		if !usedAuthRetry && apiClient.TokenProvider != nil && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+			apiClient.TokenProvider.Invalidate()
+			usedAuthRetry = true
+			continue // retry immediately with a fresh token; doesn't consume a RetryPolicy attempt
+		}
+
+		if attempt == maxAttempts || !apiClient.RetryPolicy.shouldRetry(err) {
+			return nil, totalAttempts, lastErr
+		}
+
+		timer := time.NewTimer(apiClient.RetryPolicy.backoff(attempt, retryAfterOf(err)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, totalAttempts, ctx.Err()
+		case <-timer.C:
+		}
+		attempt++
+	}
+	return nil, totalAttempts, lastErr
+}
+
+// retryAfterOf extracts the Retry-After delay from a responseStatusError's
+// headers, supporting both the delay-seconds and HTTP-date forms. It returns
+// 0 if err isn't a responseStatusError or carries no usable Retry-After value.
+func retryAfterOf(err error) time.Duration {
+	var statusErr *responseStatusError
+	if !errors.As(err, &statusErr) || statusErr.Header == nil {
+		return 0
+	}
+	value := statusErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
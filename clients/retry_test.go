@@ -0,0 +1,175 @@
+package clients
+
+import (
+	stdcontext "context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyMaxAttempts(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *RetryPolicy
+		want   int
+	}{
+		{"nil policy", nil, 1},
+		{"zero MaxAttempts", &RetryPolicy{}, 1},
+		{"negative MaxAttempts", &RetryPolicy{MaxAttempts: -1}, 1},
+		{"explicit MaxAttempts", &RetryPolicy{MaxAttempts: 3}, 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.maxAttempts(); got != tc.want {
+				t.Errorf("maxAttempts() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := &RetryPolicy{
+		RetryableStatuses: map[int]bool{http.StatusServiceUnavailable: true},
+		RetryableErrors:   func(err error) bool { return errors.Is(err, errConnReset) },
+	}
+
+	if (*RetryPolicy)(nil).shouldRetry(&responseStatusError{StatusCode: http.StatusServiceUnavailable}) {
+		t.Error("nil policy should never retry")
+	}
+	if !policy.shouldRetry(&responseStatusError{StatusCode: http.StatusServiceUnavailable}) {
+		t.Error("expected retryable status to be retried")
+	}
+	if policy.shouldRetry(&responseStatusError{StatusCode: http.StatusBadRequest}) {
+		t.Error("expected non-retryable status to not be retried")
+	}
+	if !policy.shouldRetry(errConnReset) {
+		t.Error("expected RetryableErrors-accepted error to be retried")
+	}
+	if policy.shouldRetry(errors.New("boom")) {
+		t.Error("expected error rejected by RetryableErrors to not be retried")
+	}
+
+	noErrFunc := &RetryPolicy{RetryableStatuses: map[int]bool{500: true}}
+	if noErrFunc.shouldRetry(errors.New("boom")) {
+		t.Error("expected a nil RetryableErrors to reject every non-status error")
+	}
+}
+
+var errConnReset = errors.New("connection reset")
+
+func TestRetryPolicyBackoffHonorsRetryAfter(t *testing.T) {
+	policy := &RetryPolicy{}
+	if got := policy.backoff(1, 5*time.Second); got != 5*time.Second {
+		t.Errorf("backoff() = %v, want the supplied Retry-After of 5s", got)
+	}
+}
+
+func TestRetryPolicyBackoffExponentialWithinBounds(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second}
+	wantCeilings := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+	}
+	for attempt, ceiling := range wantCeilings {
+		got := policy.backoff(attempt+1, 0)
+		if got < 0 || got > ceiling {
+			t.Errorf("backoff(%d, 0) = %v, want in [0, %v]", attempt+1, got, ceiling)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffClampsToMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}
+	got := policy.backoff(10, 0) // BaseDelay * 2^9 = 512s would far exceed MaxDelay unclamped
+	if got > 2*time.Second {
+		t.Errorf("backoff(10, 0) = %v, want clamped to MaxDelay of 2s", got)
+	}
+}
+
+func TestRetryPolicyBackoffDefaults(t *testing.T) {
+	policy := &RetryPolicy{}
+	got := policy.backoff(1, 0)
+	if got > 100*time.Millisecond {
+		t.Errorf("backoff(1, 0) with zero-value policy = %v, want within the 100ms default BaseDelay", got)
+	}
+}
+
+func TestExecuteWithRetryResendsBodyUntilSuccess(t *testing.T) {
+	var requests int
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:       3,
+			BaseDelay:         time.Millisecond,
+			RetryableStatuses: map[int]bool{http.StatusServiceUnavailable: true},
+		},
+	}
+
+	response, err := apiClient.DoPostCtx(stdcontext.Background(), testExecutionContext{}, "/widgets", "", map[string]interface{}{"name": "widget"})
+	if err != nil {
+		t.Fatalf("DoPostCtx() error = %v", err)
+	}
+	if response["ok"] != true {
+		t.Errorf("response = %v, want ok:true", response)
+	}
+	if requests != 3 {
+		t.Fatalf("server saw %d requests, want 3 (two 503s then success)", requests)
+	}
+	for i, body := range bodies {
+		if body != `{"name":"widget"}` {
+			t.Errorf("attempt %d body = %q, want %q: the buffered payload must be resent on every retry", i+1, body, `{"name":"widget"}`)
+		}
+	}
+}
+
+func TestExecuteWithRetryHonorsRetryAfter(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	apiClient := &APIClient{
+		BaseURL:    server.URL,
+		HTTPClient: server.Client(),
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:       2,
+			RetryableStatuses: map[int]bool{http.StatusServiceUnavailable: true},
+		},
+	}
+
+	start := time.Now()
+	if _, err := apiClient.DoGetCtx(stdcontext.Background(), testExecutionContext{}, "/widgets", ""); err != nil {
+		t.Fatalf("DoGetCtx() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("DoGetCtx() took %v, want >= ~1s honoring the Retry-After: 1 header", elapsed)
+	}
+}
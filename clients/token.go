@@ -0,0 +1,142 @@
+package clients
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies the bearer token a request is authorized with,
+// fetching or refreshing it as needed. Invalidate discards any cached token
+// so the next call to Token fetches a fresh one, e.g. after a 401 response.
+type TokenProvider interface {
+	Token(ctx stdcontext.Context) (string, error)
+	Invalidate()
+}
+
+// StaticTokenProvider is a TokenProvider for a fixed token that never
+// changes, e.g. a long-lived service credential supplied out of band.
+type StaticTokenProvider string
+
+// Token returns the static token unchanged.
+func (token StaticTokenProvider) Token(ctx stdcontext.Context) (string, error) {
+	return string(token), nil
+}
+
+// Invalidate is a no-op: a static token has nothing to refresh.
+func (token StaticTokenProvider) Invalidate() {}
+
+// TokenResult is a token together with when it expires, returned by the fetch
+// function a CachingTokenProvider wraps.
+type TokenResult struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+// CachingTokenProvider caches the TokenResult returned by Fetch and refreshes
+// proactively once the cached token is within Skew of expiry, so callers
+// don't pay a round trip to the token endpoint on every request.
+type CachingTokenProvider struct {
+	// Fetch retrieves a fresh token, e.g. ClientCredentialsTokenProvider.Fetch.
+	Fetch func(ctx stdcontext.Context) (TokenResult, error)
+	// Skew is how far ahead of expiry a refresh is triggered. Defaults to 30s if zero.
+	Skew time.Duration
+
+	mu        sync.Mutex
+	cached    TokenResult
+	hasCached bool
+}
+
+// Token returns the cached token if it is still valid outside of Skew,
+// otherwise it calls Fetch and caches the result.
+func (provider *CachingTokenProvider) Token(ctx stdcontext.Context) (string, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.hasCached && time.Until(provider.cached.ExpiresAt) > provider.skew() {
+		return provider.cached.AccessToken, nil
+	}
+
+	result, err := provider.Fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	provider.cached = result
+	provider.hasCached = true
+	return result.AccessToken, nil
+}
+
+// Invalidate discards the cached token so the next call to Token fetches a fresh one.
+func (provider *CachingTokenProvider) Invalidate() {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	provider.hasCached = false
+}
+
+func (provider *CachingTokenProvider) skew() time.Duration {
+	if provider.Skew <= 0 {
+		return 30 * time.Second
+	}
+	return provider.Skew
+}
+
+// ClientCredentialsTokenProvider fetches a token via the OAuth2
+// client-credentials grant. Wrap its Fetch method in a CachingTokenProvider
+// to avoid re-authenticating on every request.
+type ClientCredentialsTokenProvider struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scope        string
+	// HTTPClient is used to call TokenURL. Defaults to http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Fetch exchanges the configured client credentials for a fresh access token.
+func (provider *ClientCredentialsTokenProvider) Fetch(ctx stdcontext.Context) (TokenResult, error) {
+	httpClient := provider.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", provider.ClientID)
+	form.Set("client_secret", provider.ClientSecret)
+	if provider.Scope != "" {
+		form.Set("scope", provider.Scope)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return TokenResult{}, fmt.Errorf("Unable to create token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return TokenResult{}, fmt.Errorf("Unable to fetch token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return TokenResult{}, fmt.Errorf("Token endpoint returned status %v", response.StatusCode)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return TokenResult{}, fmt.Errorf("Unable to parse token response: %w", err)
+	}
+
+	return TokenResult{
+		AccessToken: tokenResponse.AccessToken,
+		ExpiresAt:   time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package clients
+
+import (
+	stdcontext "context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCachingTokenProviderRefetchesWithinSkew(t *testing.T) {
+	var fetches int
+	provider := &CachingTokenProvider{
+		Skew: time.Minute,
+		Fetch: func(ctx stdcontext.Context) (TokenResult, error) {
+			fetches++
+			return TokenResult{AccessToken: fmt.Sprintf("token-%d", fetches), ExpiresAt: time.Now().Add(30 * time.Second)}, nil
+		},
+	}
+
+	first, err := provider.Token(stdcontext.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	second, err := provider.Token(stdcontext.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if first == second {
+		t.Errorf("expected a fresh token once the cached one is within Skew of expiry, got %q both times", first)
+	}
+	if fetches != 2 {
+		t.Errorf("Fetch called %d times, want 2", fetches)
+	}
+}
+
+func TestCachingTokenProviderCachesOutsideSkew(t *testing.T) {
+	var fetches int
+	provider := &CachingTokenProvider{
+		Skew: time.Minute,
+		Fetch: func(ctx stdcontext.Context) (TokenResult, error) {
+			fetches++
+			return TokenResult{AccessToken: "token", ExpiresAt: time.Now().Add(time.Hour)}, nil
+		},
+	}
+
+	if _, err := provider.Token(stdcontext.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if _, err := provider.Token(stdcontext.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if fetches != 1 {
+		t.Errorf("Fetch called %d times, want 1 (token still outside Skew of expiry)", fetches)
+	}
+}
+
+func TestCachingTokenProviderInvalidateForcesRefetch(t *testing.T) {
+	var fetches int
+	provider := &CachingTokenProvider{
+		Fetch: func(ctx stdcontext.Context) (TokenResult, error) {
+			fetches++
+			return TokenResult{AccessToken: "token", ExpiresAt: time.Now().Add(time.Hour)}, nil
+		},
+	}
+
+	if _, err := provider.Token(stdcontext.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	provider.Invalidate()
+	if _, err := provider.Token(stdcontext.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Fetch called %d times, want 2 (Invalidate should force a refetch)", fetches)
+	}
+}
+
+// countingTokenProvider issues the next of its tokens on each Token call
+// (repeating the last once they run out) and counts Invalidate
+// calls, so a test can assert a 401 triggered exactly one invalidate-and-retry.
+type countingTokenProvider struct {
+	tokens      []string
+	calls       int
+	invalidated int
+}
+
+func (p *countingTokenProvider) Token(ctx stdcontext.Context) (string, error) {
+	token := p.tokens[p.calls]
+	if p.calls < len(p.tokens)-1 {
+		p.calls++
+	}
+	return token, nil
+}
+
+func (p *countingTokenProvider) Invalidate() {
+	p.invalidated++
+}
+
+func TestDoGetCtxInvalidatesTokenAndRetriesOnceOn401(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") == "Bearer stale-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	tokenProvider := &countingTokenProvider{tokens: []string{"stale-token", "fresh-token"}}
+	apiClient := &APIClient{BaseURL: server.URL, HTTPClient: server.Client(), TokenProvider: tokenProvider}
+
+	response, err := apiClient.DoGetCtx(stdcontext.Background(), testExecutionContext{}, "/widgets", "")
+	if err != nil {
+		t.Fatalf("DoGetCtx() error = %v", err)
+	}
+	if response["ok"] != true {
+		t.Errorf("response = %v, want ok:true", response)
+	}
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2 (initial 401 then one retry)", requests)
+	}
+	if tokenProvider.invalidated != 1 {
+		t.Errorf("TokenProvider.Invalidate called %d times, want 1", tokenProvider.invalidated)
+	}
+}